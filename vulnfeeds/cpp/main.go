@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
+	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/exp/slices"
 
@@ -35,6 +41,59 @@ type VendorProductToRepoMap map[VendorProduct][]string
 
 type CVEIDString string
 
+// VulnID is the canonical identifier an outcome is recorded against. It is
+// kept distinct from CVEIDString since a vulnerability can be reachable by
+// several aliases (GHSA, DSA, DLA, other NVD CVEs) and outcome tracking
+// should key on the vulnerability, not any one of its names.
+type VulnID string
+
+// canonicalIDsMu guards canonicalIDs.
+var canonicalIDsMu sync.Mutex
+
+// canonicalIDs maps every ID known to refer to a vulnerability (its CVE ID
+// plus any resolved aliases) to the single VulnID that vulnerability's
+// outcome is recorded against, so that the same issue imported under
+// several feeds collapses to one Metrics.Outcomes entry instead of one per
+// alias. Populated by registerAliases as aliases are resolved.
+var canonicalIDs = make(map[string]VulnID)
+
+// registerAliases records that CVEID and aliases all identify the same
+// vulnerability. If any of them already has a canonical VulnID assigned
+// (e.g. because it was seen as an alias of a different CVE earlier in the
+// run), that VulnID is reused for the whole group; otherwise CVEID itself
+// becomes canonical.
+func registerAliases(CVEID CVEIDString, aliases []string) {
+	if len(aliases) == 0 {
+		return
+	}
+	canonicalIDsMu.Lock()
+	defer canonicalIDsMu.Unlock()
+
+	canonical := VulnID(CVEID)
+	ids := append([]string{string(CVEID)}, aliases...)
+	for _, id := range ids {
+		if existing, ok := canonicalIDs[id]; ok {
+			canonical = existing
+			break
+		}
+	}
+	for _, id := range ids {
+		canonicalIDs[id] = canonical
+	}
+}
+
+// canonicalVulnID returns the VulnID that conversion outcomes for CVEID
+// should be recorded against: the canonical ID of its alias group if
+// registerAliases has seen it, or CVEID itself otherwise.
+func canonicalVulnID(CVEID CVEIDString) VulnID {
+	canonicalIDsMu.Lock()
+	defer canonicalIDsMu.Unlock()
+	if canonical, ok := canonicalIDs[string(CVEID)]; ok {
+		return canonical
+	}
+	return VulnID(CVEID)
+}
+
 type ConversionOutcome int
 
 var ErrNoRanges = errors.New("no ranges")
@@ -42,7 +101,7 @@ var ErrNoRanges = errors.New("no ranges")
 var ErrUnresolvedFix = errors.New("fixes not resolved to commits")
 
 func (c ConversionOutcome) String() string {
-	return [...]string{"ConversionUnknown", "Successful", "Rejected", "NoSoftware", "NoRepos", "NoRanges", "FixUnresolvable"}[c]
+	return [...]string{"ConversionUnknown", "Successful", "Rejected", "NoSoftware", "NoRepos", "NoRanges", "FixUnresolvable", "OutOfScopeLanguage", "FixInferredFromCommitLog"}[c]
 }
 
 const (
@@ -51,29 +110,136 @@ const (
 
 const (
 	// Set of enums for categorizing conversion outcomes.
-	ConversionUnknown ConversionOutcome = iota // Shouldn't happen
-	Successful                                 // It worked!
-	Rejected                                   // The CVE was rejected
-	NoSoftware                                 // The CVE had no CPEs relating to software (i.e. Operating Systems or Hardware).
-	NoRepos                                    // The CPE Vendor/Product had no repositories derived for it.
-	NoRanges                                   // No viable commit ranges could be calculated from the repository for the CVE's CPE(s).
-	FixUnresolvable                            // Partial resolution of versions, resulting in a false positive.
+	ConversionUnknown        ConversionOutcome = iota // Shouldn't happen
+	Successful                                        // It worked!
+	Rejected                                          // The CVE was rejected
+	NoSoftware                                        // The CVE had no CPEs relating to software (i.e. Operating Systems or Hardware).
+	NoRepos                                           // The CPE Vendor/Product had no repositories derived for it.
+	NoRanges                                          // No viable commit ranges could be calculated from the repository for the CVE's CPE(s).
+	FixUnresolvable                                   // Partial resolution of versions, resulting in a false positive.
+	OutOfScopeLanguage                                // None of the CVE's derived repos were written in an in-scope language.
+	FixInferredFromCommitLog                          // Fixed commits were inferred from the CVE ID being mentioned in commit logs, not tags.
 )
 
 var (
 	jsonPath            = flag.String("nvd_json", "", "Path to NVD CVE JSON to examine.")
 	parsedCPEDictionary = flag.String("cpe_repos", "", "Path to JSON mapping of CPEs to repos generated by cperepos")
 	outDir              = flag.String("out_dir", "", "Path to output results.")
-	outFormat           = flag.String("out_format", "OSV", "Format to output {OSV,PackageInfo}")
+	outFormat           = flag.String("out_format", "OSV", "Format to output {OSV,PackageInfo,CVE5}")
+	ghsaToken           = flag.String("ghsa_token", "", "GitHub token used to look up GHSA aliases via the GraphQL API.")
+	debianTrackerJSON   = flag.String("debian_tracker_json", "", "Path to a downloaded copy of the Debian security-tracker JSON feed, used to look up DSA/DLA aliases.")
+	osvMirrorDir        = flag.String("osv_mirror_dir", "", "Path to a local mirror of OSV records, used to look up other OSV-generated aliases.")
+	languages           = flag.String("languages", "C,C++", "Comma-separated list of GitHub linguist languages that make a repo in-scope.")
+	languageCachePath   = flag.String("language_cache", "", "Path to an on-disk JSON cache of repo language metadata, to survive GitHub API rate limiting.")
+	concurrency         = flag.Int("concurrency", 1, "Number of CVEs to process concurrently.")
+	progress            = flag.Bool("progress", false, "Periodically log counts of processed/successful/noRepos/fixUnresolvable CVEs while running.")
+	repoFetchTimeout    = flag.Duration("repo_fetch_timeout", 2*time.Minute, "Timeout for git operations against a single repo, so a hung clone doesn't stall the whole run.")
+	tagsCachePath       = flag.String("tags_cache", "", "Path to an on-disk cache of repo tags, to avoid refetching them on every run.")
+	tagsCacheTTL        = flag.Duration("tags_cache_ttl", 24*time.Hour, "Maximum age of a cached repo's tags before they're refetched.")
+	refreshTags         = flag.Bool("refresh_tags", false, "Ignore -tags_cache and refetch every repo's tags from scratch.")
 )
 var Logger utility.LoggerWrapper
 var RepoTagsCache git.RepoTagsCache
+
+// Aliases resolves additional identifiers for a CVE to merge into its OSV
+// record's aliases/related fields. Left nil (the zero value of the
+// interface) when none of -ghsa_token, -debian_tracker_json or
+// -osv_mirror_dir are set, in which case no alias lookups are attempted.
+var Aliases vulns.AliasResolver
+
+// LanguageCacheEntry holds the result of a prior GitHub repo languages
+// lookup, along with the ETag needed to make the next lookup a conditional
+// request.
+type LanguageCacheEntry struct {
+	Languages []string `json:"languages"`
+	ETag      string   `json:"etag"`
+}
+
+// LanguageCache is an on-disk cache of GitHub repo language metadata, keyed
+// by repo URL, to avoid re-querying (and getting rate-limited by) the
+// GitHub API on every run.
+type LanguageCache map[string]LanguageCacheEntry
+
+// InScopeLanguageCache is loaded from -language_cache at startup and
+// flushed back to it at shutdown.
+var InScopeLanguageCache = make(LanguageCache)
+
+func loadLanguageCache(path string) (LanguageCache, error) {
+	cache := make(LanguageCache)
+	if path == "" {
+		return cache, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return cache, err
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cache, err
+	}
+	return cache, nil
+}
+
+func saveLanguageCache(path string, cache LanguageCache) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
 var Metrics struct {
 	TotalCVEs           int
 	CVEsForApplications int
 	CVEsForKnownRepos   int
 	OSVRecordsGenerated int
-	Outcomes            map[CVEIDString]ConversionOutcome // Per-CVE-ID record of conversion result.
+	Outcomes            map[VulnID]ConversionOutcome // Per-vulnerability record of conversion result.
+}
+
+// MetricsMu guards all reads and writes of Metrics once CVEs are processed
+// concurrently; everything else in this file that's shared across workers
+// gets its own mutex (VPRepoCacheMu, RepoTagsCacheMu, InScopeLanguageCacheMu
+// for VPRepoCache, RepoTagsCache and InScopeLanguageCache respectively).
+var MetricsMu sync.Mutex
+
+// setOutcome records outcome for vulnID, overwriting anything already recorded.
+func setOutcome(vulnID VulnID, outcome ConversionOutcome) {
+	MetricsMu.Lock()
+	defer MetricsMu.Unlock()
+	Metrics.Outcomes[vulnID] = outcome
+}
+
+// setOutcomeIfAbsent records outcome for vulnID unless one has already been recorded,
+// so an earlier FixInferredFromCommitLog isn't clobbered by a later Successful.
+func setOutcomeIfAbsent(vulnID VulnID, outcome ConversionOutcome) {
+	MetricsMu.Lock()
+	defer MetricsMu.Unlock()
+	if _, ok := Metrics.Outcomes[vulnID]; !ok {
+		Metrics.Outcomes[vulnID] = outcome
+	}
+}
+
+// incMetric increments one of Metrics' int counters.
+func incMetric(counter *int) {
+	MetricsMu.Lock()
+	defer MetricsMu.Unlock()
+	*counter++
+}
+
+// outcomeCounts returns a snapshot of how many vulnerabilities currently carry each outcome.
+func outcomeCounts() map[ConversionOutcome]int {
+	counts := make(map[ConversionOutcome]int)
+	MetricsMu.Lock()
+	defer MetricsMu.Unlock()
+	for _, outcome := range Metrics.Outcomes {
+		counts[outcome]++
+	}
+	return counts
 }
 
 // References with these tags have been found to contain completely unrelated
@@ -120,16 +286,129 @@ func InScopeRepo(repoURL string) bool {
 
 // Use the GitHub API to query the repository's language metadata to make the determination.
 func InScopeGitHubRepo(repoURL string) bool {
-	// TODO(apollock): Implement
-	return true
+	langs, err := githubRepoLanguages(repoURL, InScopeLanguageCache)
+	if err != nil {
+		// Fail open: an API hiccup shouldn't cause an otherwise-derivable repo to be dropped.
+		Logger.Warnf("Failed to determine languages for %s, assuming in-scope: %v", repoURL, err)
+		return true
+	}
+	for _, wanted := range strings.Split(*languages, ",") {
+		if slices.Contains(langs, wanted) {
+			return true
+		}
+	}
+	return false
 }
 
+// InScopeLanguageCacheMu guards InScopeLanguageCache across concurrently-processed CVEs.
+var InScopeLanguageCacheMu sync.Mutex
+
+// githubAPIBase is overridden in tests to point at an httptest server instead of the real GitHub API.
+var githubAPIBase = "https://api.github.com"
+
+// githubRepoLanguages returns the GitHub linguist languages reported for
+// repoURL, consulting and updating cache (keyed by repo URL, conditioned on
+// ETag) to avoid needlessly reusing GitHub's API rate limit.
+func githubRepoLanguages(repoURL string, cache LanguageCache) ([]string, error) {
+	owner, repo, err := githubOwnerRepo(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	InScopeLanguageCacheMu.Lock()
+	entry := cache[repoURL]
+	InScopeLanguageCacheMu.Unlock()
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/repos/%s/%s/languages", githubAPIBase, owner, repo), nil)
+	if err != nil {
+		return nil, err
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return entry.Languages, nil
+	case http.StatusOK:
+		var languageBytes map[string]int
+		if err := json.NewDecoder(resp.Body).Decode(&languageBytes); err != nil {
+			return nil, err
+		}
+		var langs []string
+		for lang := range languageBytes {
+			langs = append(langs, lang)
+		}
+		InScopeLanguageCacheMu.Lock()
+		cache[repoURL] = LanguageCacheEntry{Languages: langs, ETag: resp.Header.Get("ETag")}
+		InScopeLanguageCacheMu.Unlock()
+		return langs, nil
+	default:
+		return nil, fmt.Errorf("GitHub languages lookup for %s/%s failed: %s", owner, repo, resp.Status)
+	}
+}
+
+// githubOwnerRepo splits a github.com repo URL into its owner and repo path components.
+func githubOwnerRepo(repoURL string) (owner, repo string, err error) {
+	parsedURL, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", err
+	}
+	parts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", "", fmt.Errorf("%q does not look like a github.com repo URL", repoURL)
+	}
+	return parts[0], parts[1], nil
+}
+
+// inScopeExtensions are the file extensions treated as indicating C/C++ source when falling back to cloning a repo directly.
+var inScopeExtensions = []string{".c", ".h", ".cc", ".cpp", ".cxx", ".hpp", ".hxx"}
+
 // Clone the repo and look for C/C++ files to make the determination.
 func InScopeGitRepo(repoURL string) bool {
-	// TODO(apollock): Implement
-	return true
+	tempDir, err := os.MkdirTemp("", "in-scope-clone-*")
+	if err != nil {
+		Logger.Warnf("Failed to create tempdir to clone %s, assuming in-scope: %v", repoURL, err)
+		return true
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *repoFetchTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "clone", "--depth", "1", "--quiet", repoURL, tempDir)
+	if err := cmd.Run(); err != nil {
+		Logger.Warnf("Failed to shallow-clone %s, assuming in-scope: %v", repoURL, err)
+		return true
+	}
+
+	inScope := false
+	err = filepath.WalkDir(tempDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || inScope || d.IsDir() {
+			return err
+		}
+		if slices.Contains(inScopeExtensions, strings.ToLower(filepath.Ext(path))) {
+			inScope = true
+		}
+		return nil
+	})
+	if err != nil {
+		Logger.Warnf("Failed to walk clone of %s, assuming in-scope: %v", repoURL, err)
+		return true
+	}
+	return inScope
 }
 
+// RepoTagsCacheMu guards only git.NormalizeRepoTags' read/populate of the
+// shared git.RepoTagsCache, not the git.VersionToCommit calls that follow
+// it -- those don't touch the cache and are network-bound, so holding the
+// lock around them would serialize the whole pipeline across goroutines
+// regardless of -concurrency.
+var RepoTagsCacheMu sync.Mutex
+
 // Examines repos and tries to convert versions to commits by treating them as Git tags.
 // Takes a CVE ID string (for logging), cves.VersionInfo with AffectedVersions and
 // typically no AffectedCommits and attempts to add AffectedCommits (including Fixed commits) where there aren't any.
@@ -138,7 +417,9 @@ func GitVersionsToCommits(CVE string, versions cves.VersionInfo, repos []string,
 	// v is a VersionInfo with AffectedCommits (containing Fixed commits) included
 	v = versions
 	for _, repo := range repos {
+		RepoTagsCacheMu.Lock()
 		normalizedTags, err := git.NormalizeRepoTags(repo, cache)
+		RepoTagsCacheMu.Unlock()
 		if err != nil {
 			Logger.Warnf("[%s]: Failed to normalize tags for %s: %v", CVE, repo, err)
 			continue
@@ -184,6 +465,51 @@ func GitVersionsToCommits(CVE string, versions cves.VersionInfo, repos []string,
 	return v, nil
 }
 
+// mergeAliases folds resolved aliases into v.Aliases or v.Related,
+// skipping anything already present (including v.ID itself, which the
+// NVD-derived CVE ID often equals). Debian DSA/DLA IDs go into Related
+// rather than Aliases: a single advisory commonly bundles fixes for
+// several unrelated CVEs, so it doesn't identify this exact vulnerability
+// the way a GHSA or another OSV ID does.
+func mergeAliases(v *vulns.Vulnerability, aliases []string) {
+	for _, alias := range aliases {
+		if alias == v.ID {
+			continue
+		}
+		if strings.HasPrefix(alias, "DSA-") || strings.HasPrefix(alias, "DLA-") {
+			if !slices.Contains(v.Related, alias) {
+				v.Related = append(v.Related, alias)
+			}
+			continue
+		}
+		if !slices.Contains(v.Aliases, alias) {
+			v.Aliases = append(v.Aliases, alias)
+		}
+	}
+}
+
+// inferCommitLogFixes is a fallback for when GitVersionsToCommits couldn't
+// resolve any Fixed commits from tags: it scans each repo's commit log for
+// mentions of CVEID and appends any matches to versions.AffectedCommits.
+// Returns whether at least one candidate fix commit was found.
+func inferCommitLogFixes(CVEID string, repos []string, versions *cves.VersionInfo) bool {
+	found := false
+	for _, repo := range repos {
+		inferred, err := git.FindFixCommitsByCVEMention(repo, CVEID)
+		if err != nil {
+			Logger.Warnf("[%s]: Failed to scan commit log of %s for CVE mentions: %v", CVEID, repo, err)
+			continue
+		}
+		if len(inferred) == 0 {
+			continue
+		}
+		Logger.Infof("[%s]: Inferred %d candidate fix commit(s) from commit log mentions in %s", CVEID, len(inferred), repo)
+		versions.AffectedCommits = append(versions.AffectedCommits, inferred...)
+		found = true
+	}
+	return found
+}
+
 func refAcceptable(ref cves.CVEReferenceData, tagDenyList []string) bool {
 	for _, deniedTag := range tagDenyList {
 		if slices.Contains(ref.Tags, deniedTag) {
@@ -193,11 +519,20 @@ func refAcceptable(ref cves.CVEReferenceData, tagDenyList []string) bool {
 	return true
 }
 
+// VPRepoCacheMu guards only the map reads/writes below, not the
+// cves.Repo/git.ValidRepo calls that surround them -- those are
+// network-bound and must be free to run concurrently across goroutines, or
+// the worker pool added in chunk0-5 serializes the whole pipeline.
+var VPRepoCacheMu sync.Mutex
+
 // Examines the CVE references for a CVE and derives repos for it, optionally caching it.
 func ReposFromReferences(CVE string, cache VendorProductToRepoMap, vp *VendorProduct, refs []cves.CVEReferenceData, tagDenyList []string) (repos []string) {
 	// This currently only gets called for cache misses, but make it not rely on that assumption.
 	if vp != nil {
-		if cachedRepos, ok := cache[*vp]; ok {
+		VPRepoCacheMu.Lock()
+		cachedRepos, ok := cache[*vp]
+		VPRepoCacheMu.Unlock()
+		if ok {
 			return cachedRepos
 		}
 	}
@@ -205,7 +540,9 @@ func ReposFromReferences(CVE string, cache VendorProductToRepoMap, vp *VendorPro
 		// If any of the denylist tags are in the ref's tag set, it's out of consideration.
 		if !refAcceptable(ref, tagDenyList) {
 			// Also remove it if previously added under an acceptable tag.
+			VPRepoCacheMu.Lock()
 			maybeRemoveFromVPRepoCache(cache, vp, ref.URL)
+			VPRepoCacheMu.Unlock()
 			Logger.Infof("[%s]: disregarding %q for %q due to a denied tag in %q", CVE, ref.URL, vp, ref.Tags)
 			break
 		}
@@ -221,7 +558,9 @@ func ReposFromReferences(CVE string, cache VendorProductToRepoMap, vp *VendorPro
 			continue
 		}
 		repos = append(repos, repo)
+		VPRepoCacheMu.Lock()
 		maybeUpdateVPRepoCache(cache, vp, repo)
+		VPRepoCacheMu.Unlock()
 	}
 	return repos
 }
@@ -247,6 +586,15 @@ func CVEToOSV(CVE cves.CVEItem, repos []string, cache git.RepoTagsCache, directo
 	versions, versionNotes := cves.ExtractVersionInfo(CVE, nil)
 	notes = append(notes, versionNotes...)
 
+	if Aliases != nil {
+		aliases, err := Aliases.Aliases(CVEID)
+		if err != nil {
+			Logger.Warnf("[%s]: Failed to resolve aliases: %v", CVEID, err)
+		}
+		mergeAliases(&v, aliases)
+		registerAliases(CVEIDString(CVEID), aliases)
+	}
+
 	if len(versions.AffectedVersions) != 0 {
 		var err error
 		// There are some AffectedVersions to try and resolve to AffectedCommits.
@@ -265,6 +613,13 @@ func CVEToOSV(CVE cves.CVEItem, repos []string, cache git.RepoTagsCache, directo
 			}
 		}
 
+		if versions.HasFixedVersions() && !hasAnyFixedCommits {
+			if inferCommitLogFixes(CVEID, repos, &versions) {
+				hasAnyFixedCommits = true
+				setOutcome(canonicalVulnID(CVEIDString(CVEID)), FixInferredFromCommitLog)
+			}
+		}
+
 		if versions.HasFixedVersions() && !hasAnyFixedCommits {
 			return fmt.Errorf("[%s]: Failed to convert fixed version tags to commits: %#v %w", CVEID, versions, ErrUnresolvedFix)
 		}
@@ -347,6 +702,13 @@ func CVEToPackageInfo(CVE cves.CVEItem, repos []string, cache git.RepoTagsCache,
 		}
 	}
 
+	if versions.HasFixedVersions() && !hasAnyFixedCommits {
+		if inferCommitLogFixes(CVEID, repos, &versions) {
+			hasAnyFixedCommits = true
+			setOutcome(canonicalVulnID(CVEIDString(CVEID)), FixInferredFromCommitLog)
+		}
+	}
+
 	if versions.HasFixedVersions() && !hasAnyFixedCommits {
 		return fmt.Errorf("[%s]: Failed to convert fixed version tags to commits: %#v %w", CVEID, versions, ErrUnresolvedFix)
 	}
@@ -398,6 +760,176 @@ func CVEToPackageInfo(CVE cves.CVEItem, repos []string, cache git.RepoTagsCache,
 	return nil
 }
 
+// Takes an NVD CVE record and outputs a CVE Services 5.0 JSON record in the specified directory.
+func CVEToCVE5(CVE cves.CVEItem, repos []string, cache git.RepoTagsCache, directory string) error {
+	CVEID := CVE.CVE.CVEDataMeta.ID // For brevity.
+	CPEs := cves.CPEs(CVE)
+	// The vendor name and product name are used to construct the output `vulnDir` below, so need to be set to *something* to keep the output tidy.
+	maybeVendorName := "ENOCPE"
+	maybeProductName := "ENOCPE"
+
+	if len(CPEs) > 0 {
+		CPE, err := cves.ParseCPE(CPEs[0]) // For naming the subdirectory used for output.
+		maybeVendorName = CPE.Vendor
+		maybeProductName = CPE.Product
+		if err != nil {
+			return fmt.Errorf("[%s]: Can't generate a CVE5 record without valid CPE data", CVEID)
+		}
+	}
+
+	versions, notes := cves.ExtractVersionInfo(CVE, nil)
+
+	if len(versions.AffectedVersions) != 0 {
+		var err error
+		if len(repos) == 0 {
+			return fmt.Errorf("[%s]: No affected ranges for %q, and no repos to try and convert %+v to tags with", CVEID, maybeProductName, versions.AffectedVersions)
+		}
+		Logger.Infof("[%s]: Trying to convert version tags %+v to commits using %v", CVEID, versions.AffectedVersions, repos)
+		versions, err = GitVersionsToCommits(CVEID, versions, repos, cache)
+		if err != nil {
+			return fmt.Errorf("[%s]: Failed to convert version tags to commits: %#v", CVEID, err)
+		}
+	}
+
+	hasAnyFixedCommits := false
+	for _, repo := range repos {
+		if versions.HasFixedCommits(repo) {
+			hasAnyFixedCommits = true
+		}
+	}
+	if versions.HasFixedVersions() && !hasAnyFixedCommits {
+		if inferCommitLogFixes(CVEID, repos, &versions) {
+			hasAnyFixedCommits = true
+			setOutcome(canonicalVulnID(CVEIDString(CVEID)), FixInferredFromCommitLog)
+		}
+	}
+	if versions.HasFixedVersions() && !hasAnyFixedCommits {
+		return fmt.Errorf("[%s]: Failed to convert fixed version tags to commits: %#v %w", CVEID, versions, ErrUnresolvedFix)
+	}
+
+	affected := cve5AffectedFromVersions(maybeVendorName, maybeProductName, repos, versions)
+	if len(affected) == 0 {
+		return fmt.Errorf("[%s]: No affected ranges detected for %q %w", CVEID, maybeProductName, ErrNoRanges)
+	}
+
+	record := vulns.CVE5Record{
+		DataType:    "CVE_RECORD",
+		DataVersion: "5.0",
+		CVEMetadata: vulns.CVE5Metadata{
+			CVEID: CVEID,
+			State: "PUBLISHED",
+		},
+		Containers: vulns.CVE5Containers{
+			CNA: vulns.CVE5CNAContainer{
+				ProviderMetadata: vulns.CVE5ProviderMetadata{OrgID: "osv.dev"},
+				Descriptions:     cve5DescriptionsFromCVE(CVE),
+				ProblemTypes:     cve5ProblemTypesFromCVE(CVE),
+				References:       cve5ReferencesFromCVE(CVE, RefTagDenyList),
+				Affected:         affected,
+			},
+		},
+	}
+
+	vulnDir := filepath.Join(directory, maybeVendorName, maybeProductName)
+	err := os.MkdirAll(vulnDir, 0755)
+	if err != nil {
+		Logger.Warnf("Failed to create dir: %v", err)
+		return fmt.Errorf("failed to create dir: %v", err)
+	}
+	outputFile := filepath.Join(vulnDir, CVEID+".cve5"+extension)
+	notesFile := filepath.Join(vulnDir, CVEID+".cve5.notes")
+	f, err := os.Create(outputFile)
+	if err != nil {
+		Logger.Warnf("Failed to open %s for writing: %v", outputFile, err)
+		return fmt.Errorf("failed to open %s for writing: %v", outputFile, err)
+	}
+	defer f.Close()
+	err = record.ToJSON(f)
+	if err != nil {
+		Logger.Warnf("Failed to write %s: %v", outputFile, err)
+		return fmt.Errorf("failed to write %s: %v", outputFile, err)
+	}
+	Logger.Infof("[%s]: Generated CVE5 record for %q", CVEID, maybeProductName)
+	if len(notes) > 0 {
+		err = os.WriteFile(notesFile, []byte(strings.Join(notes, "\n")), 0660)
+		if err != nil {
+			Logger.Warnf("[%s]: Failed to write %s: %v", CVEID, notesFile, err)
+		}
+	}
+	return nil
+}
+
+// cve5DescriptionsFromCVE maps the NVD record's description data to the CVE5 schema's descriptions array.
+func cve5DescriptionsFromCVE(CVE cves.CVEItem) (descriptions []vulns.CVE5Description) {
+	for _, d := range CVE.CVE.Description.DescriptionData {
+		descriptions = append(descriptions, vulns.CVE5Description{Lang: d.Lang, Value: d.Value})
+	}
+	return descriptions
+}
+
+// cve5ProblemTypesFromCVE maps the NVD record's CWE assignments to the CVE5 schema's problemTypes array.
+func cve5ProblemTypesFromCVE(CVE cves.CVEItem) (problemTypes []vulns.CVE5ProblemType) {
+	for _, pt := range CVE.CVE.ProblemType.ProblemTypeData {
+		var descriptions []vulns.CVE5ProblemTypeDescription
+		for _, d := range pt.Description {
+			descriptions = append(descriptions, vulns.CVE5ProblemTypeDescription{
+				Lang:        d.Lang,
+				Description: d.Value,
+				CWEID:       d.Value,
+				Type:        "CWE",
+			})
+		}
+		if len(descriptions) > 0 {
+			problemTypes = append(problemTypes, vulns.CVE5ProblemType{Descriptions: descriptions})
+		}
+	}
+	return problemTypes
+}
+
+// cve5ReferencesFromCVE maps the NVD record's references to the CVE5 schema's references array, skipping each reference that refAcceptable rejects for tagDenyList (unlike ReposFromReferences, later references are still considered).
+func cve5ReferencesFromCVE(CVE cves.CVEItem, tagDenyList []string) (references []vulns.CVE5Reference) {
+	for _, ref := range CVE.CVE.References.ReferenceData {
+		if !refAcceptable(ref, tagDenyList) {
+			continue
+		}
+		references = append(references, vulns.CVE5Reference{URL: ref.URL})
+	}
+	return references
+}
+
+// cve5AffectedFromVersions converts the resolved Git commit ranges for each repo into the CVE5 schema's affected[].versions entries.
+func cve5AffectedFromVersions(vendor, product string, repos []string, versions cves.VersionInfo) (affected []vulns.CVE5Affected) {
+	for _, repo := range repos {
+		var versionRanges []vulns.CVE5VersionRange
+		for _, ac := range versions.AffectedCommits {
+			if ac.Repo != repo {
+				continue
+			}
+			introduced := ac.Introduced
+			if introduced == "" {
+				introduced = "0"
+			}
+			vr := vulns.CVE5VersionRange{Version: introduced, Status: vulns.CVE5VersionStatusAffected, VersionType: "git"}
+			if ac.Fixed != "" {
+				vr.LessThan = ac.Fixed
+			} else if ac.LastAffected != "" {
+				vr.LessThanOrEqual = ac.LastAffected
+			}
+			versionRanges = append(versionRanges, vr)
+		}
+		if len(versionRanges) == 0 {
+			continue
+		}
+		affected = append(affected, vulns.CVE5Affected{
+			Vendor:   vendor,
+			Product:  product,
+			Repo:     repo,
+			Versions: versionRanges,
+		})
+	}
+	return affected
+}
+
 func loadCPEDictionary(ProductToRepo *VendorProductToRepoMap, f string) error {
 	data, err := os.ReadFile(f)
 	if err != nil {
@@ -443,7 +975,7 @@ func maybeRemoveFromVPRepoCache(cache VendorProductToRepoMap, vp *VendorProduct,
 }
 
 // Output a CSV summarizing per-CVE how it was handled.
-func outputOutcomes(outcomes map[CVEIDString]ConversionOutcome, reposForCVE map[CVEIDString][]string, directory string) error {
+func outputOutcomes(outcomes map[VulnID]ConversionOutcome, reposForCVE map[CVEIDString][]string, directory string) error {
 	outcomesFile, err := os.Create(filepath.Join(directory, "outcomes.csv"))
 	if err != nil {
 		return err
@@ -451,13 +983,13 @@ func outputOutcomes(outcomes map[CVEIDString]ConversionOutcome, reposForCVE map[
 	defer outcomesFile.Close()
 	w := csv.NewWriter(outcomesFile)
 	w.Write([]string{"CVE", "outcome", "repos"})
-	for CVE, outcome := range outcomes {
+	for vulnID, outcome := range outcomes {
 		// It's conceivable to have more than one repo for a CVE, so concatenate them.
 		r := ""
-		if repos, ok := reposForCVE[CVE]; ok {
+		if repos, ok := reposForCVE[CVEIDString(vulnID)]; ok {
 			r = strings.Join(repos, " ")
 		}
-		w.Write([]string{string(CVE), outcome.String(), r})
+		w.Write([]string{string(vulnID), outcome.String(), r})
 	}
 	w.Flush()
 
@@ -467,19 +999,250 @@ func outputOutcomes(outcomes map[CVEIDString]ConversionOutcome, reposForCVE map[
 	return nil
 }
 
+// processCVE carries a single NVD CVE record through repo derivation,
+// in-scope filtering and conversion, recording its outcome in Metrics along
+// the way. It returns the repos that were ultimately derived for the CVE
+// (possibly none), for the outcomes CSV.
+func processCVE(cve cves.CVEItem, VPRepoCache VendorProductToRepoMap) (repos []string) {
+	refs := cve.CVE.References.ReferenceData
+	CPEs := cves.CPEs(cve)
+	CVEID := CVEIDString(cve.CVE.CVEDataMeta.ID)
+
+	if len(refs) == 0 && len(CPEs) == 0 {
+		Logger.Infof("[%s]: skipping due to lack of CPEs and lack of references", CVEID)
+		// 100% of these in 2022 were rejected CVEs
+		setOutcome(canonicalVulnID(CVEID), Rejected)
+		return nil
+	}
+
+	// Edge case: No CPEs, but perhaps usable references.
+	if len(refs) > 0 && len(CPEs) == 0 {
+		derived := ReposFromReferences(string(CVEID), nil, nil, refs, RefTagDenyList)
+		if len(derived) == 0 {
+			Logger.Warnf("[%s]: Failed to derive any repos and there were no CPEs", CVEID)
+			return nil
+		}
+		Logger.Infof("[%s]: Derived %q for CVE with no CPEs", CVEID, derived)
+		repos = derived
+	}
+
+	// Does it have any application CPEs? Look for pre-computed repos based on VendorProduct.
+	appCPECount := 0
+	for _, CPEstr := range CPEs {
+		CPE, err := cves.ParseCPE(CPEstr)
+		if err != nil {
+			Logger.Warnf("[%s]: Failed to parse CPE %q: %+v", CVEID, CPEstr, err)
+			setOutcome(canonicalVulnID(CVEID), ConversionUnknown)
+			continue
+		}
+		if CPE.Part == "a" {
+			appCPECount += 1
+		}
+		VPRepoCacheMu.Lock()
+		cachedRepos, ok := VPRepoCache[VendorProduct{CPE.Vendor, CPE.Product}]
+		VPRepoCacheMu.Unlock()
+		if ok {
+			Logger.Infof("[%s]: Pre-references, derived %q for %q %q using cache", CVEID, cachedRepos, CPE.Vendor, CPE.Product)
+			for _, repo := range cachedRepos {
+				if !slices.Contains(repos, repo) {
+					repos = append(repos, repo)
+				}
+			}
+		}
+	}
+
+	if len(CPEs) > 0 && appCPECount == 0 {
+		// This CVE is not for software (based on there being CPEs but not any application ones), skip.
+		setOutcome(canonicalVulnID(CVEID), NoSoftware)
+		return nil
+	}
+
+	if appCPECount > 0 {
+		incMetric(&Metrics.CVEsForApplications)
+	}
+
+	// If there wasn't a repo from the CPE Dictionary, try and derive one from the CVE references.
+	if len(repos) == 0 && len(refs) > 0 {
+		for _, CPEstr := range CPEs {
+			CPE, err := cves.ParseCPE(CPEstr)
+			if err != nil {
+				Logger.Warnf("[%s]: Failed to parse CPE %q: %+v", CVEID, CPEstr, err)
+				continue
+			}
+			// Continue to only focus on application CPEs.
+			if CPE.Part != "a" {
+				continue
+			}
+			if slices.Contains(VendorProductDenyList, VendorProduct{CPE.Vendor, ""}) {
+				continue
+			}
+			if slices.Contains(VendorProductDenyList, VendorProduct{CPE.Vendor, CPE.Product}) {
+				continue
+			}
+			derived := ReposFromReferences(string(CVEID), VPRepoCache, &VendorProduct{CPE.Vendor, CPE.Product}, refs, RefTagDenyList)
+			if len(derived) == 0 {
+				Logger.Warnf("[%s]: Failed to derive any repos for %q %q", CVEID, CPE.Vendor, CPE.Product)
+				continue
+			}
+			Logger.Infof("[%s]: Derived %q for %q %q", CVEID, derived, CPE.Vendor, CPE.Product)
+			repos = derived
+		}
+	}
+
+	Logger.Infof("[%s]: Summary: [CPEs=%d AppCPEs=%d DerivedRepos=%d]", CVEID, len(CPEs), appCPECount, len(repos))
+
+	// If we've made it to here, we may have a CVE:
+	// * that has Application-related CPEs (so applies to software)
+	// * has a reference that is a known repository URL
+	// OR
+	// * a derived repository for the software package
+	//
+	// We do not yet have:
+	// * any knowledge of the language used
+	// * definitive version information
+
+	if len(repos) == 0 {
+		// We have nothing useful to work with, so we'll assume it's out of scope
+		Logger.Infof("[%s]: Passing due to lack of viable repository", CVEID)
+		setOutcome(canonicalVulnID(CVEID), NoRepos)
+		return nil
+	}
+
+	Logger.Infof("[%s]: Repos: %#v", CVEID, repos)
+
+	var inScopeRepos []string
+	for _, repo := range repos {
+		if InScopeRepo(repo) {
+			inScopeRepos = append(inScopeRepos, repo)
+		}
+	}
+	if len(inScopeRepos) == 0 {
+		Logger.Infof("[%s]: Passing due to lack of in-scope (language) repos", CVEID)
+		setOutcome(canonicalVulnID(CVEID), OutOfScopeLanguage)
+		return nil
+	}
+	repos = inScopeRepos
+
+	incMetric(&Metrics.CVEsForKnownRepos)
+
+	var err error
+	switch *outFormat {
+	case "OSV":
+		err = CVEToOSV(cve, repos, RepoTagsCache, *outDir)
+	case "PackageInfo":
+		err = CVEToPackageInfo(cve, repos, RepoTagsCache, *outDir)
+	case "CVE5":
+		err = CVEToCVE5(cve, repos, RepoTagsCache, *outDir)
+	}
+	// Parse this error to determine which failure mode it was
+	if err != nil {
+		Logger.Warnf("[%s]: Failed to generate an OSV record: %+v", CVEID, err)
+		switch {
+		case errors.Is(err, ErrNoRanges):
+			setOutcome(canonicalVulnID(CVEID), NoRanges)
+		case errors.Is(err, ErrUnresolvedFix):
+			setOutcome(canonicalVulnID(CVEID), FixUnresolvable)
+		default:
+			setOutcome(canonicalVulnID(CVEID), ConversionUnknown)
+		}
+		return repos
+	}
+	incMetric(&Metrics.OSVRecordsGenerated)
+	// CVEToOSV/CVEToPackageInfo/CVEToCVE5 may have already recorded FixInferredFromCommitLog
+	// for this vuln ID to distinguish commit-log-inferred fixes from tag-derived ones.
+	setOutcomeIfAbsent(canonicalVulnID(CVEID), Successful)
+	return repos
+}
+
+// runConcurrently runs work over each of items, at most concurrency at a
+// time, and waits for all of them to finish. A concurrency of 1 processes
+// items serially, in order.
+func runConcurrently(items []cves.CVEItem, concurrency int, work func(cves.CVEItem)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, item := range items {
+		item := item
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			work(item)
+		}()
+	}
+	wg.Wait()
+}
+
+// startProgressLogger, when -progress is set, periodically logs counts of
+// processed/successful/noRepos/fixUnresolvable CVEs against total until the
+// returned stop function is called.
+func startProgressLogger(total int) (stop func()) {
+	if !*progress {
+		return func() {}
+	}
+	done := make(chan struct{})
+	ticker := time.NewTicker(10 * time.Second)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				counts := outcomeCounts()
+				processed := 0
+				for _, n := range counts {
+					processed += n
+				}
+				Logger.Infof("Progress: processed=%d/%d successful=%d noRepos=%d fixUnresolvable=%d",
+					processed, total, counts[Successful]+counts[FixInferredFromCommitLog], counts[NoRepos], counts[FixUnresolvable])
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
 func main() {
 	flag.Parse()
-	if !slices.Contains([]string{"OSV", "PackageInfo"}, *outFormat) {
+	if !slices.Contains([]string{"OSV", "PackageInfo", "CVE5"}, *outFormat) {
 		fmt.Fprintf(os.Stderr, "Unsupported output format: %s\n", *outFormat)
 		os.Exit(1)
 	}
 
-	Metrics.Outcomes = make(map[CVEIDString]ConversionOutcome)
+	Metrics.Outcomes = make(map[VulnID]ConversionOutcome)
+
+	var resolvers []vulns.AliasResolver
+	if *ghsaToken != "" {
+		resolvers = append(resolvers, vulns.GHSAAliasResolver{Token: *ghsaToken})
+	}
+	if *debianTrackerJSON != "" {
+		resolvers = append(resolvers, &vulns.DebianAliasResolver{TrackerJSONPath: *debianTrackerJSON})
+	}
+	if *osvMirrorDir != "" {
+		resolvers = append(resolvers, &vulns.OSVMirrorAliasResolver{MirrorDir: *osvMirrorDir})
+	}
+	if len(resolvers) > 0 {
+		Aliases = vulns.MultiAliasResolver{Resolvers: resolvers}
+	}
 
 	var logCleanup func()
 	Logger, logCleanup = utility.CreateLoggerWrapper("cpp-osv")
 	defer logCleanup()
 
+	var err error
+	InScopeLanguageCache, err = loadLanguageCache(*languageCachePath)
+	if err != nil {
+		Logger.Warnf("Failed to load language cache from %s: %v", *languageCachePath, err)
+	}
+	defer func() {
+		if err := saveLanguageCache(*languageCachePath, InScopeLanguageCache); err != nil {
+			Logger.Warnf("Failed to save language cache to %s: %v", *languageCachePath, err)
+		}
+	}()
+
 	data, err := os.ReadFile(*jsonPath)
 	if err != nil {
 		Logger.Fatalf("Failed to open file: %v", err) // double check this is best practice output
@@ -501,148 +1264,39 @@ func main() {
 		Logger.Infof("VendorProductToRepoMap cache has %d entries preloaded", len(VPRepoCache))
 	}
 
-	ReposForCVE := make(map[CVEIDString][]string)
-
-	for _, cve := range parsed.CVEItems {
-		refs := cve.CVE.References.ReferenceData
-		CPEs := cves.CPEs(cve)
-		CVEID := CVEIDString(cve.CVE.CVEDataMeta.ID)
-
-		if len(refs) == 0 && len(CPEs) == 0 {
-			Logger.Infof("[%s]: skipping due to lack of CPEs and lack of references", CVEID)
-			// 100% of these in 2022 were rejected CVEs
-			Metrics.Outcomes[CVEID] = Rejected
-			continue
-		}
-
-		// Edge case: No CPEs, but perhaps usable references.
-		if len(refs) > 0 && len(CPEs) == 0 {
-			repos := ReposFromReferences(string(CVEID), nil, nil, refs, RefTagDenyList)
-			if len(repos) == 0 {
-				Logger.Warnf("[%s]: Failed to derive any repos and there were no CPEs", CVEID)
-				continue
-			}
-			Logger.Infof("[%s]: Derived %q for CVE with no CPEs", CVEID, repos)
-			ReposForCVE[CVEID] = repos
-		}
+	git.FetchTimeout = *repoFetchTimeout
 
-		// Does it have any application CPEs? Look for pre-computed repos based on VendorProduct.
-		appCPECount := 0
-		for _, CPEstr := range cves.CPEs(cve) {
-			CPE, err := cves.ParseCPE(CPEstr)
-			if err != nil {
-				Logger.Warnf("[%s]: Failed to parse CPE %q: %+v", cve.CVE.CVEDataMeta.ID, CPEstr, err)
-				Metrics.Outcomes[CVEID] = ConversionUnknown
-				continue
-			}
-			if CPE.Part == "a" {
-				appCPECount += 1
-			}
-			if _, ok := VPRepoCache[VendorProduct{CPE.Vendor, CPE.Product}]; ok {
-				Logger.Infof("[%s]: Pre-references, derived %q for %q %q using cache", CVEID, VPRepoCache[VendorProduct{CPE.Vendor, CPE.Product}], CPE.Vendor, CPE.Product)
-				if _, ok := ReposForCVE[CVEID]; !ok {
-					ReposForCVE[CVEID] = VPRepoCache[VendorProduct{CPE.Vendor, CPE.Product}]
-					continue
-				}
-				// Don't append duplicates.
-				for _, repo := range VPRepoCache[VendorProduct{CPE.Vendor, CPE.Product}] {
-					if !slices.Contains(ReposForCVE[CVEID], repo) {
-						ReposForCVE[CVEID] = append(ReposForCVE[CVEID], repo)
-					}
-				}
-			}
-		}
-
-		if len(CPEs) > 0 && appCPECount == 0 {
-			// This CVE is not for software (based on there being CPEs but not any application ones), skip.
-			Metrics.Outcomes[CVEID] = NoSoftware
-			continue
-		}
-
-		if appCPECount > 0 {
-			Metrics.CVEsForApplications++
+	if !*refreshTags {
+		RepoTagsCache, err = git.LoadRepoTagsCache(*tagsCachePath, *tagsCacheTTL)
+		if err != nil {
+			Logger.Warnf("Failed to load tags cache from %s: %v", *tagsCachePath, err)
+			RepoTagsCache = make(git.RepoTagsCache)
 		}
-
-		// If there wasn't a repo from the CPE Dictionary, try and derive one from the CVE references.
-		if _, ok := ReposForCVE[CVEID]; !ok && len(refs) > 0 {
-			for _, CPEstr := range cves.CPEs(cve) {
-				CPE, err := cves.ParseCPE(CPEstr)
-				if err != nil {
-					Logger.Warnf("[%s]: Failed to parse CPE %q: %+v", CVEID, CPEstr, err)
-					continue
-				}
-				// Continue to only focus on application CPEs.
-				if CPE.Part != "a" {
-					continue
-				}
-				if slices.Contains(VendorProductDenyList, VendorProduct{CPE.Vendor, ""}) {
-					continue
-				}
-				if slices.Contains(VendorProductDenyList, VendorProduct{CPE.Vendor, CPE.Product}) {
-					continue
-				}
-				repos := ReposFromReferences(string(CVEID), VPRepoCache, &VendorProduct{CPE.Vendor, CPE.Product}, refs, RefTagDenyList)
-				if len(repos) == 0 {
-					Logger.Warnf("[%s]: Failed to derive any repos for %q %q", CVEID, CPE.Vendor, CPE.Product)
-					continue
-				}
-				Logger.Infof("[%s]: Derived %q for %q %q", CVEID, repos, CPE.Vendor, CPE.Product)
-				ReposForCVE[CVEID] = repos
-			}
+	} else {
+		RepoTagsCache = make(git.RepoTagsCache)
+	}
+	defer func() {
+		if err := git.SaveRepoTagsCache(*tagsCachePath, RepoTagsCache); err != nil {
+			Logger.Warnf("Failed to save tags cache to %s: %v", *tagsCachePath, err)
 		}
+	}()
 
-		Logger.Infof("[%s]: Summary: [CPEs=%d AppCPEs=%d DerivedRepos=%d]", CVEID, len(CPEs), appCPECount, len(ReposForCVE[CVEID]))
-
-		// If we've made it to here, we may have a CVE:
-		// * that has Application-related CPEs (so applies to software)
-		// * has a reference that is a known repository URL
-		// OR
-		// * a derived repository for the software package
-		//
-		// We do not yet have:
-		// * any knowledge of the language used
-		// * definitive version information
-
-		if _, ok := ReposForCVE[CVEID]; !ok {
-			// We have nothing useful to work with, so we'll assume it's out of scope
-			Logger.Infof("[%s]: Passing due to lack of viable repository", CVEID)
-			Metrics.Outcomes[CVEID] = NoRepos
-			continue
-		}
+	ReposForCVE := make(map[CVEIDString][]string)
+	var ReposForCVEMu sync.Mutex
 
-		Logger.Infof("[%s]: Repos: %#v", CVEID, ReposForCVE[CVEID])
+	stopProgress := startProgressLogger(len(parsed.CVEItems))
+	defer stopProgress()
 
-		for _, repo := range ReposForCVE[CVEID] {
-			if !InScopeRepo(repo) {
-				continue
-			}
+	runConcurrently(parsed.CVEItems, *concurrency, func(cve cves.CVEItem) {
+		CVEID := CVEIDString(cve.CVE.CVEDataMeta.ID)
+		repos := processCVE(cve, VPRepoCache)
+		if len(repos) > 0 {
+			ReposForCVEMu.Lock()
+			ReposForCVE[CVEID] = repos
+			ReposForCVEMu.Unlock()
 		}
+	})
 
-		Metrics.CVEsForKnownRepos++
-
-		switch *outFormat {
-		case "OSV":
-			err = CVEToOSV(cve, ReposForCVE[CVEID], RepoTagsCache, *outDir)
-		case "PackageInfo":
-			err = CVEToPackageInfo(cve, ReposForCVE[CVEID], RepoTagsCache, *outDir)
-		}
-		// Parse this error to determine which failure mode it was
-		if err != nil {
-			Logger.Warnf("[%s]: Failed to generate an OSV record: %+v", CVEID, err)
-			if errors.Is(err, ErrNoRanges) {
-				Metrics.Outcomes[CVEID] = NoRanges
-				continue
-			}
-			if errors.Is(err, ErrUnresolvedFix) {
-				Metrics.Outcomes[CVEID] = FixUnresolvable
-				continue
-			}
-			Metrics.Outcomes[CVEID] = ConversionUnknown
-			continue
-		}
-		Metrics.OSVRecordsGenerated++
-		Metrics.Outcomes[CVEID] = Successful
-	}
 	Metrics.TotalCVEs = len(parsed.CVEItems)
 	err = outputOutcomes(Metrics.Outcomes, ReposForCVE, *outDir)
 	if err != nil {
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/osv/vulnfeeds/cves"
+)
+
+// BenchmarkRunConcurrently exercises the worker pool dispatch overhead
+// together with the shared VPRepoCache that real CVE processing guards
+// with VPRepoCacheMu. Each unit of work sleeps briefly outside the lock (to
+// stand in for a network-bound repo lookup) and then takes the lock only
+// to update the cache, mirroring the narrowed locking in
+// ReposFromReferences. Higher concurrency should visibly reduce wall time
+// here; if it doesn't, a lock is being held across the simulated I/O again.
+func BenchmarkRunConcurrently(b *testing.B) {
+	items := make([]cves.CVEItem, 200)
+
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			cache := make(VendorProductToRepoMap)
+			for i := 0; i < b.N; i++ {
+				runConcurrently(items, concurrency, func(item cves.CVEItem) {
+					time.Sleep(time.Millisecond)
+					vp := VendorProduct{Vendor: "vendor", Product: "product"}
+					VPRepoCacheMu.Lock()
+					maybeUpdateVPRepoCache(cache, &vp, "https://example.com/repo")
+					VPRepoCacheMu.Unlock()
+				})
+			}
+		})
+	}
+}
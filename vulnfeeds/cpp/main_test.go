@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGithubRepoLanguagesCacheMiss(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc123"`)
+		w.Write([]byte(`{"C":1000,"C++":500}`))
+	}))
+	defer server.Close()
+
+	oldBase := githubAPIBase
+	githubAPIBase = server.URL
+	defer func() { githubAPIBase = oldBase }()
+
+	cache := make(LanguageCache)
+	langs, err := githubRepoLanguages("https://github.com/example/repo", cache)
+	if err != nil {
+		t.Fatalf("githubRepoLanguages() failed: %v", err)
+	}
+	if len(langs) != 2 {
+		t.Errorf("githubRepoLanguages() = %v, want 2 languages", langs)
+	}
+
+	entry, ok := cache["https://github.com/example/repo"]
+	if !ok {
+		t.Fatalf("githubRepoLanguages() did not populate the cache")
+	}
+	if entry.ETag != `"abc123"` {
+		t.Errorf("cached ETag = %q, want %q", entry.ETag, `"abc123"`)
+	}
+}
+
+func TestGithubRepoLanguagesCacheHit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") != `"abc123"` {
+			t.Errorf("request did not carry the cached ETag, got If-None-Match=%q", r.Header.Get("If-None-Match"))
+		}
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	oldBase := githubAPIBase
+	githubAPIBase = server.URL
+	defer func() { githubAPIBase = oldBase }()
+
+	cache := LanguageCache{
+		"https://github.com/example/repo": LanguageCacheEntry{Languages: []string{"C"}, ETag: `"abc123"`},
+	}
+	langs, err := githubRepoLanguages("https://github.com/example/repo", cache)
+	if err != nil {
+		t.Fatalf("githubRepoLanguages() failed: %v", err)
+	}
+	if len(langs) != 1 || langs[0] != "C" {
+		t.Errorf("githubRepoLanguages() = %v, want the cached [C] to be returned unchanged", langs)
+	}
+	if requests != 1 {
+		t.Errorf("server received %d requests, want 1", requests)
+	}
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/osv/vulnfeeds/cves"
+)
+
+// Case-insensitive to match the "-i" used in the git log --grep call below.
+var cveMentionPattern = regexp.MustCompile(`(?i)CVE-\d{4}-\d{4,}`)
+
+// FetchTimeout bounds how long any single git network operation in this
+// package (cloning, fetching) is allowed to take, so a hung repo doesn't
+// stall an entire run. Callers that expose a flag for this should set it
+// before processing begins.
+var FetchTimeout = 2 * time.Minute
+
+// FindFixCommitsByCVEMention scans repo's commit log for messages that
+// mention cveID and returns each matching commit as a candidate Fixed
+// commit, for use when GitVersionsToCommits couldn't resolve a fix from
+// tagged versions. This mirrors the approach taken by vuls and OE-core's
+// CVE tooling, which treat a commit message mentioning the CVE ID as
+// sufficient evidence the commit fixed it, even when the fix never made it
+// into a tagged release.
+func FindFixCommitsByCVEMention(repo string, cveID string) ([]cves.AffectedCommit, error) {
+	if !cveMentionPattern.MatchString(cveID) {
+		return nil, fmt.Errorf("%q does not look like a CVE ID", cveID)
+	}
+
+	tempDir, err := os.MkdirTemp("", "cve-mention-clone-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tempdir to clone %s: %w", repo, err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), FetchTimeout)
+	defer cancel()
+
+	// This needs the full commit history to grep, but not the file contents at
+	// each commit, so a blob-less partial clone with no working tree gets the
+	// same answer as a full clone at a fraction of the network/disk cost --
+	// important since this runs per-CVE, on top of the shallow clone that
+	// InScopeGitRepo makes of the same repo moments earlier when it isn't on
+	// github.com.
+	cloneArgs := []string{"clone", "--quiet", "--no-checkout", "--filter=blob:none", repo, tempDir}
+	if err := exec.CommandContext(ctx, "git", cloneArgs...).Run(); err != nil {
+		return nil, fmt.Errorf("failed to clone %s: %w", repo, err)
+	}
+
+	out, err := exec.CommandContext(ctx, "git", "-C", tempDir, "log", "--format=%H", "--grep", cveID, "-i").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan commit log of %s for %q: %w", repo, cveID, err)
+	}
+
+	var commits []cves.AffectedCommit
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		hash := strings.TrimSpace(scanner.Text())
+		if hash == "" {
+			continue
+		}
+		commits = append(commits, cves.AffectedCommit{Repo: repo, Fixed: hash})
+	}
+	return commits, scanner.Err()
+}
@@ -0,0 +1,29 @@
+package git
+
+import "testing"
+
+func TestCveMentionPattern(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"CVE-2022-1234", true},
+		{"cve-2022-1234", true},
+		{"CVE-2022-123456789", true},
+		{"CVE-22-1234", false},
+		{"not a cve", false},
+	}
+	for _, tt := range tests {
+		if got := cveMentionPattern.MatchString(tt.id); got != tt.want {
+			t.Errorf("cveMentionPattern.MatchString(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestFindFixCommitsByCVEMentionRejectsInvalidID(t *testing.T) {
+	// An invalid CVE ID should be rejected before any clone is attempted, so
+	// this must not touch the network even with FetchTimeout at its default.
+	if _, err := FindFixCommitsByCVEMention("https://example.com/repo", "not-a-cve-id"); err == nil {
+		t.Errorf("FindFixCommitsByCVEMention() with an invalid CVE ID = nil error, want an error")
+	}
+}
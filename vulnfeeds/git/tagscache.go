@@ -0,0 +1,81 @@
+package git
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"time"
+)
+
+// tagsCacheSchemaVersion is bumped whenever the on-disk format of
+// RepoTagsCache changes incompatibly, so an old cache file is ignored
+// (rather than failing to decode, or worse, decoding into garbage) after an
+// upgrade.
+const tagsCacheSchemaVersion = 1
+
+// tagsCacheFile is the on-disk envelope around a RepoTagsCache: a schema
+// version for forward-compatibility, and the time it was saved, so a
+// caller can apply a TTL without having to stat the file itself.
+type tagsCacheFile struct {
+	SchemaVersion int
+	SavedAt       time.Time
+	Cache         RepoTagsCache
+}
+
+// LoadRepoTagsCache reads a RepoTagsCache previously written by
+// SaveRepoTagsCache. It returns an empty cache, rather than an error, if
+// path doesn't exist yet, carries an unrecognized schema version, or is
+// older than ttl -- all of these just mean starting fresh, not a hard
+// failure.
+func LoadRepoTagsCache(path string, ttl time.Duration) (RepoTagsCache, error) {
+	if path == "" {
+		return make(RepoTagsCache), nil
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return make(RepoTagsCache), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var onDisk tagsCacheFile
+	if err := gob.NewDecoder(f).Decode(&onDisk); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	if onDisk.SchemaVersion != tagsCacheSchemaVersion {
+		return make(RepoTagsCache), nil
+	}
+	if ttl > 0 && time.Since(onDisk.SavedAt) > ttl {
+		return make(RepoTagsCache), nil
+	}
+	if onDisk.Cache == nil {
+		return make(RepoTagsCache), nil
+	}
+	return onDisk.Cache, nil
+}
+
+// SaveRepoTagsCache writes cache to path, stamped with the current schema
+// version and time, for a future LoadRepoTagsCache call to pick up.
+func SaveRepoTagsCache(path string, cache RepoTagsCache) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	onDisk := tagsCacheFile{
+		SchemaVersion: tagsCacheSchemaVersion,
+		SavedAt:       time.Now(),
+		Cache:         cache,
+	}
+	if err := gob.NewEncoder(f).Encode(&onDisk); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	return nil
+}
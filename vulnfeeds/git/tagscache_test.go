@@ -0,0 +1,104 @@
+package git
+
+import (
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRepoTagsCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags_cache")
+	want := RepoTagsCache{"https://example.com/repo": nil}
+
+	if err := SaveRepoTagsCache(path, want); err != nil {
+		t.Fatalf("SaveRepoTagsCache() failed: %v", err)
+	}
+
+	got, err := LoadRepoTagsCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("LoadRepoTagsCache() failed: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Errorf("LoadRepoTagsCache() = %v entries, want %v", len(got), len(want))
+	}
+	for repo := range want {
+		if _, ok := got[repo]; !ok {
+			t.Errorf("LoadRepoTagsCache() is missing entry for %q", repo)
+		}
+	}
+}
+
+func TestLoadRepoTagsCacheMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist")
+
+	got, err := LoadRepoTagsCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("LoadRepoTagsCache() failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("LoadRepoTagsCache() = %v, want empty cache", got)
+	}
+}
+
+func TestLoadRepoTagsCacheSchemaMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags_cache")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("os.Create() failed: %v", err)
+	}
+	stale := tagsCacheFile{
+		SchemaVersion: tagsCacheSchemaVersion + 1,
+		SavedAt:       time.Now(),
+		Cache:         RepoTagsCache{"https://example.com/repo": nil},
+	}
+	if err := gob.NewEncoder(f).Encode(&stale); err != nil {
+		t.Fatalf("Encode() failed: %v", err)
+	}
+	f.Close()
+
+	got, err := LoadRepoTagsCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("LoadRepoTagsCache() failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("LoadRepoTagsCache() with mismatched schema version = %v, want empty cache", got)
+	}
+}
+
+func TestLoadRepoTagsCacheExpired(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tags_cache")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("os.Create() failed: %v", err)
+	}
+	stale := tagsCacheFile{
+		SchemaVersion: tagsCacheSchemaVersion,
+		SavedAt:       time.Now().Add(-2 * time.Hour),
+		Cache:         RepoTagsCache{"https://example.com/repo": nil},
+	}
+	if err := gob.NewEncoder(f).Encode(&stale); err != nil {
+		t.Fatalf("Encode() failed: %v", err)
+	}
+	f.Close()
+
+	got, err := LoadRepoTagsCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("LoadRepoTagsCache() failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("LoadRepoTagsCache() with an expired save = %v, want empty cache", got)
+	}
+
+	// A zero TTL disables expiry altogether.
+	got, err = LoadRepoTagsCache(path, 0)
+	if err != nil {
+		t.Fatalf("LoadRepoTagsCache() with ttl=0 failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("LoadRepoTagsCache() with ttl=0 = %v entries, want 1", len(got))
+	}
+}
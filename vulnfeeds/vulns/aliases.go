@@ -0,0 +1,240 @@
+package vulns
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+)
+
+// AliasResolver looks up additional identifiers (GHSA, DSA, DLA, other
+// OSV-generated IDs, ...) known to refer to the same vulnerability as the
+// given CVE ID, so they can be merged into an OSV record's aliases/related
+// fields.
+type AliasResolver interface {
+	Aliases(cveID string) ([]string, error)
+}
+
+// MultiAliasResolver queries a list of AliasResolvers and merges their
+// results, deduplicated, so several alias sources can be consulted for a
+// single CVE without the caller needing to know which ones are configured.
+type MultiAliasResolver struct {
+	Resolvers []AliasResolver
+}
+
+func (m MultiAliasResolver) Aliases(cveID string) (aliases []string, err error) {
+	for _, r := range m.Resolvers {
+		found, resolverErr := r.Aliases(cveID)
+		if resolverErr != nil {
+			// Best-effort: one source being unavailable shouldn't prevent the others from contributing.
+			continue
+		}
+		for _, a := range found {
+			if !slices.Contains(aliases, a) {
+				aliases = append(aliases, a)
+			}
+		}
+	}
+	return aliases, nil
+}
+
+// GHSAAliasResolver finds GitHub Security Advisory IDs for a CVE using the
+// GitHub GraphQL API's securityAdvisories(identifier:) query.
+type GHSAAliasResolver struct {
+	// Token is a GitHub personal access token used to authenticate the GraphQL request.
+	Token string
+	// Endpoint overrides the GitHub GraphQL endpoint, for testing.
+	Endpoint string
+}
+
+type ghsaGraphQLResponse struct {
+	Data struct {
+		SecurityAdvisories struct {
+			Nodes []struct {
+				GHSAID string `json:"ghsaId"`
+			} `json:"nodes"`
+		} `json:"securityAdvisories"`
+	} `json:"data"`
+}
+
+func (g GHSAAliasResolver) Aliases(cveID string) (aliases []string, err error) {
+	endpoint := g.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.github.com/graphql"
+	}
+	query, err := json.Marshal(map[string]string{
+		"query": fmt.Sprintf(`query{securityAdvisories(identifier:{type:CVE,value:%q}){nodes{ghsaId}}}`, cveID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(string(query)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GHSA lookup for %s failed: %s", cveID, resp.Status)
+	}
+
+	var parsed ghsaGraphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, err
+	}
+	for _, node := range parsed.Data.SecurityAdvisories.Nodes {
+		aliases = append(aliases, node.GHSAID)
+	}
+	return aliases, nil
+}
+
+// DebianAliasResolver finds Debian Security Advisory (DSA) and Debian LTS
+// Advisory (DLA) IDs for a CVE from a local copy of the Debian
+// security-tracker's JSON feed (https://security-tracker.debian.org/tracker/data/json).
+//
+// The feed is parsed into a by-CVE index on the first call to Aliases and
+// reused after that, since it's walked once per process rather than once
+// per CVE (a real tracker feed covers tens of thousands of CVEs).
+type DebianAliasResolver struct {
+	// TrackerJSONPath is the path to a downloaded copy of the security-tracker JSON feed.
+	TrackerJSONPath string
+
+	indexOnce sync.Once
+	index     map[string][]string
+	indexErr  error
+}
+
+// debianTrackerEntry models the handful of fields used out of the
+// security-tracker's per-package, per-CVE records.
+type debianTrackerEntry struct {
+	Releases map[string]struct {
+		Advisories []string `json:"advisories"`
+	} `json:"releases"`
+}
+
+func (d *DebianAliasResolver) buildIndex() {
+	d.indexOnce.Do(func() {
+		data, err := os.ReadFile(d.TrackerJSONPath)
+		if err != nil {
+			d.indexErr = err
+			return
+		}
+		// The feed is keyed by package name, then CVE ID, so it has to be walked in full.
+		var tracker map[string]map[string]debianTrackerEntry
+		if err := json.Unmarshal(data, &tracker); err != nil {
+			d.indexErr = err
+			return
+		}
+		index := make(map[string][]string)
+		for _, cves := range tracker {
+			for cveID, entry := range cves {
+				for _, release := range entry.Releases {
+					for _, advisory := range release.Advisories {
+						if !slices.Contains(index[cveID], advisory) {
+							index[cveID] = append(index[cveID], advisory)
+						}
+					}
+				}
+			}
+		}
+		d.index = index
+	})
+}
+
+func (d *DebianAliasResolver) Aliases(cveID string) ([]string, error) {
+	d.buildIndex()
+	if d.indexErr != nil {
+		return nil, d.indexErr
+	}
+	return d.index[cveID], nil
+}
+
+// OSVMirrorAliasResolver finds other OSV-generated IDs for a CVE from a
+// local mirror of OSV records (one JSON file per ID). A record counts as
+// an alias match for cveID either if its own "aliases" field lists cveID,
+// or if its "id" field *is* cveID (this tool's own -out_format=OSV output
+// writes records whose ID is the bare CVE ID, so that record's aliases
+// need to be surfaced too, not just records that mention the CVE as an
+// alias of themselves).
+//
+// The mirror is walked into a by-CVE index on the first call to Aliases
+// and reused after that, since a real mirror directory has far more files
+// than the feed has CVEs to look up.
+type OSVMirrorAliasResolver struct {
+	// MirrorDir is the root of a directory tree of OSV records, as produced by `osv.dev`'s exporter.
+	MirrorDir string
+
+	indexOnce sync.Once
+	index     map[string][]string
+	indexErr  error
+}
+
+type osvMirrorRecord struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+}
+
+func (o *OSVMirrorAliasResolver) buildIndex() {
+	o.indexOnce.Do(func() {
+		index := make(map[string][]string)
+		o.indexErr = filepath.WalkDir(o.MirrorDir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || filepath.Ext(path) != ".json" {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				// Best-effort: skip files we can't read rather than aborting the whole walk.
+				return nil
+			}
+			var record osvMirrorRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return nil
+			}
+			if record.ID == "" {
+				return nil
+			}
+			for _, alias := range record.Aliases {
+				if alias == record.ID {
+					continue
+				}
+				if !slices.Contains(index[alias], record.ID) {
+					index[alias] = append(index[alias], record.ID)
+				}
+			}
+			// The record's own ID is itself a valid query key: a record whose ID
+			// is the CVE being looked up should surface its own aliases too.
+			for _, alias := range record.Aliases {
+				if alias == record.ID {
+					continue
+				}
+				if !slices.Contains(index[record.ID], alias) {
+					index[record.ID] = append(index[record.ID], alias)
+				}
+			}
+			return nil
+		})
+		o.index = index
+	})
+}
+
+func (o *OSVMirrorAliasResolver) Aliases(cveID string) ([]string, error) {
+	o.buildIndex()
+	if o.indexErr != nil {
+		return nil, o.indexErr
+	}
+	return o.index[cveID], nil
+}
@@ -0,0 +1,99 @@
+package vulns
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// CVE5Record models the subset of the CVE Services 5.0 JSON schema
+// (https://github.com/CVEProject/cve-schema) that cpp/main.go populates from
+// an NVD CVE record. It intentionally omits ADP containers and the fields
+// CVE Services assigns at submission time (e.g. state transitions), since
+// this package only ever produces CNA-authored records for local output.
+type CVE5Record struct {
+	DataType    string         `json:"dataType"`
+	DataVersion string         `json:"dataVersion"`
+	CVEMetadata CVE5Metadata   `json:"cveMetadata"`
+	Containers  CVE5Containers `json:"containers"`
+}
+
+type CVE5Metadata struct {
+	CVEID string `json:"cveId"`
+	State string `json:"state"`
+}
+
+type CVE5Containers struct {
+	CNA CVE5CNAContainer `json:"cna"`
+}
+
+// CVE5CNAContainer is the "cnaContainer" of the schema, carrying everything
+// the reporting CNA (in this case, derived from the NVD record) asserts
+// about the vulnerability.
+type CVE5CNAContainer struct {
+	ProviderMetadata CVE5ProviderMetadata `json:"providerMetadata"`
+	Descriptions     []CVE5Description    `json:"descriptions"`
+	ProblemTypes     []CVE5ProblemType    `json:"problemTypes,omitempty"`
+	References       []CVE5Reference      `json:"references,omitempty"`
+	Affected         []CVE5Affected       `json:"affected,omitempty"`
+}
+
+type CVE5ProviderMetadata struct {
+	OrgID string `json:"orgId"`
+}
+
+type CVE5Description struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+type CVE5ProblemType struct {
+	Descriptions []CVE5ProblemTypeDescription `json:"descriptions"`
+}
+
+type CVE5ProblemTypeDescription struct {
+	Lang        string `json:"lang"`
+	Description string `json:"description"`
+	CWEID       string `json:"cweId,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+type CVE5Reference struct {
+	URL string `json:"url"`
+}
+
+// CVE5Affected is one entry of the schema's "affected" array. Repo holds the
+// Git repository the versions were resolved against, which isn't part of
+// the upstream schema's required fields but is carried here so downstream
+// consumers can tell which of possibly several repos a range applies to.
+type CVE5Affected struct {
+	Vendor   string             `json:"vendor,omitempty"`
+	Product  string             `json:"product,omitempty"`
+	Repo     string             `json:"repo,omitempty"`
+	Versions []CVE5VersionRange `json:"versions"`
+}
+
+// CVE5VersionRange mirrors the schema's git-style version range entries, as
+// produced from a resolved cves.AffectedCommit: "affected" starting at
+// Version (typically "0" when only an upper bound is known) and running up
+// to, but not including, LessThan, or up to and including LessThanOrEqual
+// when only the last affected commit is known.
+type CVE5VersionRange struct {
+	Version         string `json:"version"`
+	Status          string `json:"status"`
+	VersionType     string `json:"versionType,omitempty"`
+	LessThan        string `json:"lessThan,omitempty"`
+	LessThanOrEqual string `json:"lessThanOrEqual,omitempty"`
+}
+
+const (
+	CVE5VersionStatusAffected   = "affected"
+	CVE5VersionStatusUnaffected = "unaffected"
+)
+
+// ToJSON writes the record to w as indented JSON, matching the style CVE
+// Services itself emits.
+func (r *CVE5Record) ToJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,63 @@
+package vulns
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestCVE5RecordRoundTrip(t *testing.T) {
+	want := CVE5Record{
+		DataType:    "CVE_RECORD",
+		DataVersion: "5.0",
+		CVEMetadata: CVE5Metadata{
+			CVEID: "CVE-2022-1234",
+			State: "PUBLISHED",
+		},
+		Containers: CVE5Containers{
+			CNA: CVE5CNAContainer{
+				ProviderMetadata: CVE5ProviderMetadata{OrgID: "osv.dev"},
+				Descriptions: []CVE5Description{
+					{Lang: "en", Value: "An example vulnerability for round-trip testing."},
+				},
+				ProblemTypes: []CVE5ProblemType{
+					{
+						Descriptions: []CVE5ProblemTypeDescription{
+							{Lang: "en", Description: "Out-of-bounds write", CWEID: "CWE-787", Type: "CWE"},
+						},
+					},
+				},
+				References: []CVE5Reference{
+					{URL: "https://example.com/advisory"},
+				},
+				Affected: []CVE5Affected{
+					{
+						Vendor:  "example",
+						Product: "libexample",
+						Repo:    "https://github.com/example/libexample",
+						Versions: []CVE5VersionRange{
+							{Version: "0", Status: CVE5VersionStatusAffected, VersionType: "git", LessThan: "deadbeefcafe"},
+							{Version: "deadbeefcafe", Status: CVE5VersionStatusUnaffected, VersionType: "git"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := want.ToJSON(&buf); err != nil {
+		t.Fatalf("ToJSON() failed: %v", err)
+	}
+
+	var got CVE5Record
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if !bytes.Equal(gotJSON, wantJSON) {
+		t.Errorf("round-tripped record differs:\ngot:  %s\nwant: %s", gotJSON, wantJSON)
+	}
+}